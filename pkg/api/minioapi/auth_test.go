@@ -0,0 +1,173 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	mstorage "github.com/minio-io/minio/pkg/storage"
+	"github.com/minio-io/minio/pkg/utils/config"
+)
+
+func TestBucketObjectFromPath(t *testing.T) {
+	testCases := []struct {
+		path       string
+		wantBucket string
+		wantObject string
+	}{
+		{"/", "", ""},
+		{"/bucket", "bucket", ""},
+		{"/bucket/object", "bucket", "object"},
+		{"/bucket/path/to/object", "bucket", "path/to/object"},
+	}
+	for _, test := range testCases {
+		bucket, object := bucketObjectFromPath(test.path)
+		if bucket != test.wantBucket || object != test.wantObject {
+			t.Errorf("bucketObjectFromPath(%q) = (%q, %q), want (%q, %q)",
+				test.path, bucket, object, test.wantBucket, test.wantObject)
+		}
+	}
+}
+
+// TestPolicyAllowsAnonymous is the decision table for anonymous access:
+// every (policy, object-or-bucket-level, method) combination that
+// matters to bucketPolicyHandler's security posture.
+func TestPolicyAllowsAnonymous(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy mstorage.BucketPolicy
+		object string
+		method string
+		query  url.Values
+		want   bool
+	}{
+		{"private bucket denies anonymous GET", mstorage.BucketPrivate, "key", "GET", nil, false},
+		{"private bucket denies anonymous PUT", mstorage.BucketPrivate, "key", "PUT", nil, false},
+		{"public-read allows anonymous GET", mstorage.BucketPublicRead, "key", "GET", nil, true},
+		{"public-read allows anonymous HEAD", mstorage.BucketPublicRead, "key", "HEAD", nil, true},
+		{"public-read denies anonymous PUT", mstorage.BucketPublicRead, "key", "PUT", nil, false},
+		{"public-read denies anonymous DELETE", mstorage.BucketPublicRead, "key", "DELETE", nil, false},
+		{"public-read-write allows anonymous PUT on an object", mstorage.BucketPublicReadWrite, "key", "PUT", nil, true},
+		{"public-read-write allows anonymous DELETE on an object", mstorage.BucketPublicReadWrite, "key", "DELETE", nil, true},
+		{"public-read-write allows anonymous bucket listing", mstorage.BucketPublicReadWrite, "", "GET", nil, true},
+		{"public-read-write denies anonymous bucket deletion", mstorage.BucketPublicReadWrite, "", "DELETE", nil, false},
+		{"public-read-write denies anonymous bucket-policy reads", mstorage.BucketPublicReadWrite, "", "GET", url.Values{"policy": {""}}, false},
+		{"public-read-write denies anonymous bucket-policy writes", mstorage.BucketPublicReadWrite, "key", "PUT", url.Values{"policy": {""}}, false},
+		{"public-read-write allows anonymous batch delete", mstorage.BucketPublicReadWrite, "", "POST", url.Values{"delete": {""}}, true},
+		{"public-read-write denies anonymous bucket POST without delete", mstorage.BucketPublicReadWrite, "", "POST", nil, false},
+		{"public-read denies anonymous batch delete", mstorage.BucketPublicRead, "", "POST", url.Values{"delete": {""}}, false},
+	}
+
+	for _, test := range testCases {
+		storage := newFakeStorage()
+		storage.policies["bucket"] = test.policy
+
+		got := policyAllowsAnonymous(storage, "bucket", test.object, test.method, test.query)
+		if got != test.want {
+			t.Errorf("%s: policyAllowsAnonymous(...) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPolicyAllowsAnonymousUnknownBucketDenies(t *testing.T) {
+	storage := newFakeStorage()
+	if policyAllowsAnonymous(storage, "no-such-bucket", "key", "GET", nil) {
+		t.Error("expected a bucket with no stored policy to deny anonymous access")
+	}
+}
+
+func TestBucketPolicyHandlerDeniesAnonymousListBuckets(t *testing.T) {
+	storage := newFakeStorage()
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("inner handler should not be reached for an anonymous bucket listing request")
+	})
+	handler := bucketPolicyHandler(storage, inner)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != errorCodeResponses[accessDenied].HTTPStatusCode {
+		t.Errorf("expected %d, got %d", errorCodeResponses[accessDenied].HTTPStatusCode, w.Code)
+	}
+}
+
+func TestBucketPolicyHandlerAllowsAnonymousPublicRead(t *testing.T) {
+	storage := newFakeStorage()
+	storage.policies["bucket"] = mstorage.BucketPublicRead
+	reached := false
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := bucketPolicyHandler(storage, inner)
+
+	req := httptest.NewRequest("GET", "/bucket/object", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if !reached {
+		t.Fatal("expected request to reach the inner handler")
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+}
+
+func TestBucketPolicyHandlerIgnoresClientSuppliedAuthenticatedHeader(t *testing.T) {
+	storage := newFakeStorage() // bucket has no stored policy: private by default
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("inner handler should not be reached: a forged header must not bypass policy checks")
+	})
+	handler := bucketPolicyHandler(storage, inner)
+
+	// The "authenticated" decision lives in the request context, not an
+	// HTTP header, specifically so a client can't forge it by setting a
+	// header of the same name.
+	req := httptest.NewRequest("DELETE", "/bucket/object", nil)
+	req.Header.Set("X-Minio-Authenticated", "true")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != errorCodeResponses[accessDenied].HTTPStatusCode {
+		t.Errorf("expected %d, got %d", errorCodeResponses[accessDenied].HTTPStatusCode, w.Code)
+	}
+}
+
+func TestSignatureHandlerMarksVerifiedRequestAuthenticated(t *testing.T) {
+	reached := false
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated, _ := r.Context().Value(authenticatedContextKey{}).(bool)
+		if !authenticated {
+			t.Error("expected request to be marked authenticated after a valid signature")
+		}
+		reached = true
+	})
+	handler := signatureHandler(config.Config{}, inner)
+
+	req := httptest.NewRequest("GET", "/bucket/object", nil)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 irrelevant-for-this-fake-validator")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if !reached {
+		t.Fatal("expected inner handler to be reached")
+	}
+}
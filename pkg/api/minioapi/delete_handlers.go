@@ -0,0 +1,127 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"encoding/xml"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	mstorage "github.com/minio-io/minio/pkg/storage"
+)
+
+// deleteObject is a single <Object> entry of a batched Delete request.
+type deleteObject struct {
+	Key string
+}
+
+// deleteRequest is the request body of POST /{bucket}?delete.
+type deleteRequest struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool
+	Object  []deleteObject `xml:"Object"`
+}
+
+// deletedObject is a successfully deleted entry in a DeleteResult.
+type deletedObject struct {
+	Key string
+}
+
+// deleteError is a failed entry in a DeleteResult.
+type deleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+// DeleteResult is the XML response of POST /{bucket}?delete.
+type DeleteResult struct {
+	XMLName xml.Name        `xml:"DeleteResult"`
+	Deleted []deletedObject `xml:"Deleted"`
+	Error   []deleteError   `xml:"Error"`
+}
+
+// deleteBucketHandler - DELETE /{bucket}
+// Removes an empty bucket.
+func (api minioApi) deleteBucketHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	err := api.storage.DeleteBucket(bucket)
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case mstorage.ObjectNotFound:
+		writeErrorResponse(w, req, noSuchBucket, bucket, "")
+	default:
+		writeErrorResponse(w, req, internalError, bucket, "")
+	}
+}
+
+// deleteObjectHandler - DELETE /{bucket}/{object}
+// Removes a single object. Like S3, deleting an object that doesn't
+// exist is not an error.
+func (api minioApi) deleteObjectHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	err := api.storage.DeleteObject(bucket, object)
+	switch err.(type) {
+	case nil, mstorage.ObjectNotFound:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrorResponse(w, req, internalError, bucket, object)
+	}
+}
+
+// deleteMultipleObjectsHandler - POST /{bucket}?delete
+// Parses the S3 "Delete" XML request body and removes each of the
+// listed objects, returning per-key success/error status in the
+// DeleteResult response.
+func (api minioApi) deleteMultipleObjectsHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	var request deleteRequest
+	if err := xml.NewDecoder(req.Body).Decode(&request); err != nil {
+		writeErrorResponse(w, req, malformedXML, bucket, "")
+		return
+	}
+
+	var result DeleteResult
+	for _, obj := range request.Object {
+		switch err := api.storage.DeleteObject(bucket, obj.Key); err.(type) {
+		case nil, mstorage.ObjectNotFound:
+			if !request.Quiet {
+				result.Deleted = append(result.Deleted, deletedObject{Key: obj.Key})
+			}
+		default:
+			result.Error = append(result.Error, deleteError{
+				Key:     obj.Key,
+				Code:    errorCodeResponses[internalError].Code,
+				Message: errorCodeResponses[internalError].Description,
+			})
+		}
+	}
+
+	encodeErr := writeSuccessResponse(w, result)
+	if encodeErr != nil {
+		log.Println(encodeErr)
+	}
+}
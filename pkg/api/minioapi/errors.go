@@ -0,0 +1,112 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// errorCode identifies one of the S3-compatible error responses this
+// server can return.
+type errorCode int
+
+const (
+	internalError errorCode = iota
+	invalidPart
+	noSuchUpload
+	malformedXML
+	accessDenied
+	noSuchBucket
+	notImplemented
+)
+
+// errorCodeInfo carries the HTTP status and S3 error code/message
+// triplet for a given errorCode.
+type errorCodeInfo struct {
+	Code           string
+	Description    string
+	HTTPStatusCode int
+}
+
+var errorCodeResponses = map[errorCode]errorCodeInfo{
+	internalError: {
+		Code:           "InternalError",
+		Description:    "We encountered an internal error, please try again.",
+		HTTPStatusCode: http.StatusInternalServerError,
+	},
+	invalidPart: {
+		Code:           "InvalidPart",
+		Description:    "One or more of the specified parts could not be found.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	noSuchUpload: {
+		Code:           "NoSuchUpload",
+		Description:    "The specified multipart upload does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	malformedXML: {
+		Code:           "MalformedXML",
+		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	accessDenied: {
+		Code:           "AccessDenied",
+		Description:    "Access Denied.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	noSuchBucket: {
+		Code:           "NoSuchBucket",
+		Description:    "The specified bucket does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	notImplemented: {
+		Code:           "NotImplemented",
+		Description:    "A header or query you provided requested a function that is not implemented.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+}
+
+// errorResponse is the XML body returned alongside an error status code.
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string
+	Message   string
+	Resource  string
+	RequestID string `xml:"RequestId"`
+}
+
+// writeErrorResponse writes an S3-compatible XML error body and sets
+// the matching HTTP status code.
+func writeErrorResponse(w http.ResponseWriter, req *http.Request, code errorCode, bucket, object string) {
+	info := errorCodeResponses[code]
+
+	resource := "/" + bucket
+	if object != "" {
+		resource += "/" + object
+	}
+
+	response := errorResponse{
+		Code:     info.Code,
+		Message:  info.Description,
+		Resource: resource,
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(info.HTTPStatusCode)
+	xml.NewEncoder(w).Encode(response)
+}
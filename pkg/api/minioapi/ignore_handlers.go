@@ -0,0 +1,73 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import "net/http"
+
+// unimplementedBucketResources and unimplementedObjectResources list
+// the S3 subresources this server does not support. Any request whose
+// query string carries one of these is rejected before it reaches the
+// mux so that an unsupported feature fails loudly instead of silently
+// falling through to an unrelated handler.
+//
+// uploads/uploadId/partNumber (multipart) and policy/delete (bucket
+// policy, batch delete) are intentionally absent from these lists:
+// they are fully implemented and must reach the mux.
+var unimplementedBucketResources = map[string]bool{
+	"accelerate":     true,
+	"acl":            true,
+	"cors":           true,
+	"lifecycle":      true,
+	"logging":        true,
+	"notification":   true,
+	"replication":    true,
+	"requestPayment": true,
+	"tagging":        true,
+	"versioning":     true,
+	"website":        true,
+}
+
+var unimplementedObjectResources = map[string]bool{
+	"acl":     true,
+	"tagging": true,
+	"torrent": true,
+}
+
+// ignoreUnimplementedResources rejects requests for S3 subresources
+// this server doesn't implement, so that clients get a clear error
+// instead of being routed to a handler for a semantically different
+// operation.
+func ignoreUnimplementedResources(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		for resource := range unimplementedBucketResources {
+			if _, ok := query[resource]; ok {
+				bucket, object := bucketObjectFromPath(req.URL.Path)
+				writeErrorResponse(w, req, notImplemented, bucket, object)
+				return
+			}
+		}
+		for resource := range unimplementedObjectResources {
+			if _, ok := query[resource]; ok {
+				bucket, object := bucketObjectFromPath(req.URL.Path)
+				writeErrorResponse(w, req, notImplemented, bucket, object)
+				return
+			}
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
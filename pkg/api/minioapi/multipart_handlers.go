@@ -0,0 +1,347 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	mstorage "github.com/minio-io/minio/pkg/storage"
+)
+
+// InitiateMultipartUploadResult is the XML response for POST ?uploads.
+type InitiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Bucket   string
+	Key      string
+	UploadID string `xml:"UploadId"`
+}
+
+// completeMultipartUploadPart mirrors a single <Part> entry in the
+// CompleteMultipartUpload request body.
+type completeMultipartUploadPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// completeMultipartUpload is the request body of POST ?uploadId=....
+type completeMultipartUpload struct {
+	XMLName xml.Name                      `xml:"CompleteMultipartUpload"`
+	Part    []completeMultipartUploadPart `xml:"Part"`
+}
+
+// CompleteMultipartUploadResult is the XML response for POST ?uploadId=....
+type CompleteMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Location string
+	Bucket   string
+	Key      string
+	ETag     string
+}
+
+// ListPartsResult is the XML response for GET ?uploadId=....
+type ListPartsResult struct {
+	XMLName              xml.Name `xml:"ListPartsResult"`
+	Bucket               string
+	Key                  string
+	UploadID             string `xml:"UploadId"`
+	PartNumberMarker     int
+	NextPartNumberMarker int
+	MaxParts             int
+	IsTruncated          bool
+	Part                 []Part `xml:"Part"`
+}
+
+// Part describes a single uploaded part inside a ListPartsResult.
+type Part struct {
+	PartNumber   int
+	ETag         string
+	LastModified string
+	Size         int64
+}
+
+// ListMultipartUploadsResult is the XML response for GET /{bucket}?uploads.
+type ListMultipartUploadsResult struct {
+	XMLName            xml.Name `xml:"ListMultipartUploadsResult"`
+	Bucket             string
+	KeyMarker          string
+	UploadIDMarker     string `xml:"UploadIdMarker"`
+	NextKeyMarker      string
+	NextUploadIDMarker string `xml:"NextUploadIdMarker"`
+	Delimiter          string
+	Prefix             string
+	MaxUploads         int
+	IsTruncated        bool
+	Upload             []Upload `xml:"Upload"`
+}
+
+// Upload describes a single in-progress multipart upload inside a
+// ListMultipartUploadsResult.
+type Upload struct {
+	Key       string
+	UploadID  string `xml:"UploadId"`
+	Initiated string
+}
+
+// initMultipartUploadHandler - POST /{bucket}/{object}?uploads
+// Initiates a new multipart upload and returns an upload ID that
+// subsequent part uploads must reference.
+func (api minioApi) initMultipartUploadHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	uploadID, err := api.storage.NewMultipartUpload(bucket, object, req.Header.Get("Content-Type"))
+	if err != nil {
+		writeErrorResponse(w, req, internalError, bucket, object)
+		return
+	}
+
+	response := InitiateMultipartUploadResult{
+		Bucket:   bucket,
+		Key:      object,
+		UploadID: uploadID,
+	}
+	encodeErr := writeSuccessResponse(w, response)
+	if encodeErr != nil {
+		log.Println(encodeErr)
+	}
+}
+
+// putObjectPartHandler - PUT /{bucket}/{object}?partNumber=N&uploadId=...
+// Stages a single part of a multipart upload. The returned ETag is the
+// MD5 of the part's contents, same as a regular PUT object.
+func (api minioApi) putObjectPartHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	// partNumber is a query parameter, not a route variable: the route
+	// matches on uploadId alone so that a missing or non-numeric
+	// partNumber is rejected here with invalidPart rather than falling
+	// through to the unrelated whole-object putObjectHandler route.
+	partNumber, err := strconv.Atoi(req.URL.Query().Get("partNumber"))
+	if err != nil || partNumber <= 0 {
+		writeErrorResponse(w, req, invalidPart, bucket, object)
+		return
+	}
+
+	partMD5, err := api.storage.CreateObjectPart(bucket, object, uploadID, partNumber,
+		req.Header.Get("Content-Type"), req.Header.Get("Content-MD5"), req.Body)
+	switch err.(type) {
+	case nil:
+		w.Header().Set("ETag", "\""+partMD5+"\"")
+	case mstorage.ObjectNotFound:
+		writeErrorResponse(w, req, noSuchUpload, bucket, object)
+	default:
+		writeErrorResponse(w, req, internalError, bucket, object)
+	}
+}
+
+// completeMultipartUploadHandler - POST /{bucket}/{object}?uploadId=...
+// Assembles the previously staged parts into the final object. The
+// response ETag is the S3-style multipart ETag computed from the
+// client-supplied part ETags - not a plain content MD5 - so that it
+// matches what any S3 client expects: the raw MD5 bytes of each part,
+// concatenated in part order, MD5'd again and suffixed with the part
+// count.
+func (api minioApi) completeMultipartUploadHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	var parts completeMultipartUpload
+	if err := xml.NewDecoder(req.Body).Decode(&parts); err != nil {
+		writeErrorResponse(w, req, malformedXML, bucket, object)
+		return
+	}
+
+	completeParts := make([]mstorage.CompletePart, len(parts.Part))
+	for i, part := range parts.Part {
+		completeParts[i] = mstorage.CompletePart{
+			PartNumber: part.PartNumber,
+			ETag:       part.ETag,
+		}
+	}
+
+	etag, err := multipartETag(parts.Part)
+	if err != nil {
+		writeErrorResponse(w, req, invalidPart, bucket, object)
+		return
+	}
+
+	_, err = api.storage.CompleteMultipartUpload(bucket, object, uploadID, completeParts)
+	switch err.(type) {
+	case nil:
+		response := CompleteMultipartUploadResult{
+			Location: req.URL.Path,
+			Bucket:   bucket,
+			Key:      object,
+			ETag:     etag,
+		}
+		encodeErr := writeSuccessResponse(w, response)
+		if encodeErr != nil {
+			log.Println(encodeErr)
+		}
+	case mstorage.ObjectNotFound:
+		writeErrorResponse(w, req, noSuchUpload, bucket, object)
+	default:
+		writeErrorResponse(w, req, internalError, bucket, object)
+	}
+}
+
+// multipartETag computes the S3-style multipart ETag for a completed
+// upload: each part's ETag (the MD5 of that part's contents, as
+// returned by putObjectPartHandler) is hex-decoded back to raw bytes,
+// those bytes are concatenated in part order, and the concatenation is
+// MD5'd again and hex-encoded, with "-<part count>" appended.
+func multipartETag(parts []completeMultipartUploadPart) (string, error) {
+	h := md5.New()
+	for _, part := range parts {
+		raw, err := hex.DecodeString(strings.Trim(part.ETag, "\""))
+		if err != nil {
+			return "", err
+		}
+		h.Write(raw)
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(h.Sum(nil)), len(parts)), nil
+}
+
+// abortMultipartUploadHandler - DELETE /{bucket}/{object}?uploadId=...
+// Discards an in-progress multipart upload along with any staged parts.
+func (api minioApi) abortMultipartUploadHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	err := api.storage.AbortMultipartUpload(bucket, object, uploadID)
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case mstorage.ObjectNotFound:
+		writeErrorResponse(w, req, noSuchUpload, bucket, object)
+	default:
+		writeErrorResponse(w, req, internalError, bucket, object)
+	}
+}
+
+// listObjectPartsHandler - GET /{bucket}/{object}?uploadId=...
+// Lists the parts that have been staged so far against an in-progress
+// multipart upload.
+func (api minioApi) listObjectPartsHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	partNumberMarker, _ := strconv.Atoi(req.URL.Query().Get("part-number-marker"))
+	maxParts, err := strconv.Atoi(req.URL.Query().Get("max-parts"))
+	if err != nil || maxParts <= 0 {
+		maxParts = 1000
+	}
+
+	result, err := api.storage.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
+	if err != nil {
+		writeErrorResponse(w, req, noSuchUpload, bucket, object)
+		return
+	}
+
+	response := ListPartsResult{
+		Bucket:               result.Bucket,
+		Key:                  result.Key,
+		UploadID:             result.UploadID,
+		PartNumberMarker:     result.PartNumberMarker,
+		NextPartNumberMarker: result.NextPartNumberMarker,
+		MaxParts:             result.MaxParts,
+		IsTruncated:          result.IsTruncated,
+	}
+	for _, part := range result.Parts {
+		response.Part = append(response.Part, Part{
+			PartNumber:   part.PartNumber,
+			ETag:         part.ETag,
+			LastModified: part.LastModified.Format(dateFormat),
+			Size:         part.Size,
+		})
+	}
+
+	encodeErr := writeSuccessResponse(w, response)
+	if encodeErr != nil {
+		log.Println(encodeErr)
+	}
+}
+
+// listMultipartUploadsHandler - GET /{bucket}?uploads
+// Lists multipart uploads that have been initiated but not yet
+// completed or aborted.
+func (api minioApi) listMultipartUploadsHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	query := req.URL.Query()
+	maxUploads, err := strconv.Atoi(query.Get("max-uploads"))
+	if err != nil || maxUploads <= 0 {
+		maxUploads = 1000
+	}
+
+	params := mstorage.UploadsListParams{
+		Delimiter:      query.Get("delimiter"),
+		KeyMarker:      query.Get("key-marker"),
+		MaxUploads:     maxUploads,
+		Prefix:         query.Get("prefix"),
+		UploadIDMarker: query.Get("upload-id-marker"),
+	}
+
+	result, err := api.storage.ListMultipartUploads(bucket, params)
+	if err != nil {
+		writeErrorResponse(w, req, internalError, bucket, "")
+		return
+	}
+
+	response := ListMultipartUploadsResult{
+		Bucket:             result.Bucket,
+		KeyMarker:          result.KeyMarker,
+		UploadIDMarker:     result.UploadIDMarker,
+		NextKeyMarker:      result.NextKeyMarker,
+		NextUploadIDMarker: result.NextUploadIDMarker,
+		Delimiter:          result.Delimiter,
+		Prefix:             result.Prefix,
+		MaxUploads:         result.MaxUploads,
+		IsTruncated:        result.IsTruncated,
+	}
+	for _, upload := range result.Uploads {
+		response.Upload = append(response.Upload, Upload{
+			Key:       upload.Key,
+			UploadID:  upload.UploadID,
+			Initiated: upload.Initiated.Format(dateFormat),
+		})
+	}
+
+	encodeErr := writeSuccessResponse(w, response)
+	if encodeErr != nil {
+		log.Println(encodeErr)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func newMultipartRequest(t *testing.T, method, rawQuery, body string, vars map[string]string) *http.Request {
+	req := httptest.NewRequest(method, "/bucket/object?"+rawQuery, strings.NewReader(body))
+	req.URL.RawQuery = rawQuery
+	return mux.SetURLVars(req, vars)
+}
+
+func TestPutObjectPartHandlerReturnsETag(t *testing.T) {
+	storage := newFakeStorage()
+	storage.nextUploadID = "upload-1"
+	if _, err := storage.NewMultipartUpload("bucket", "object", "text/plain"); err != nil {
+		t.Fatalf("unexpected error priming upload: %s", err)
+	}
+	api := minioApi{storage: storage}
+
+	req := newMultipartRequest(t, "PUT", "partNumber=1&uploadId=upload-1", "hello",
+		map[string]string{"bucket": "bucket", "object": "object", "uploadId": "upload-1"})
+	w := httptest.NewRecorder()
+
+	api.putObjectPartHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	want := "\"" + md5Hex([]byte("hello")) + "\""
+	if got := w.Header().Get("ETag"); got != want {
+		t.Errorf("ETag = %q, want %q", got, want)
+	}
+}
+
+func TestPutObjectPartHandlerRejectsInvalidPartNumber(t *testing.T) {
+	storage := newFakeStorage()
+	api := minioApi{storage: storage}
+
+	testCases := []string{"", "0", "-1", "abc"}
+	for _, partNumber := range testCases {
+		req := newMultipartRequest(t, "PUT", "partNumber="+url.QueryEscape(partNumber)+"&uploadId=upload-1", "body",
+			map[string]string{"bucket": "bucket", "object": "object", "uploadId": "upload-1"})
+		w := httptest.NewRecorder()
+
+		api.putObjectPartHandler(w, req)
+
+		if w.Code != errorCodeResponses[invalidPart].HTTPStatusCode {
+			t.Errorf("partNumber=%q: expected %d, got %d", partNumber, errorCodeResponses[invalidPart].HTTPStatusCode, w.Code)
+		}
+	}
+}
+
+func TestCompleteMultipartUploadHandlerAssemblesETag(t *testing.T) {
+	storage := newFakeStorage()
+	storage.nextUploadID = "upload-1"
+	if _, err := storage.NewMultipartUpload("bucket", "object", "text/plain"); err != nil {
+		t.Fatalf("unexpected error priming upload: %s", err)
+	}
+	storage.uploads["upload-1"][1] = "hello, "
+	storage.uploads["upload-1"][2] = "world"
+
+	api := minioApi{storage: storage}
+
+	part1ETag := md5Hex([]byte("hello, "))
+	part2ETag := md5Hex([]byte("world"))
+	body := `<CompleteMultipartUpload>
+		<Part><PartNumber>1</PartNumber><ETag>` + part1ETag + `</ETag></Part>
+		<Part><PartNumber>2</PartNumber><ETag>` + part2ETag + `</ETag></Part>
+	</CompleteMultipartUpload>`
+	req := newMultipartRequest(t, "POST", "uploadId=upload-1", body,
+		map[string]string{"bucket": "bucket", "object": "object", "uploadId": "upload-1"})
+	w := httptest.NewRecorder()
+
+	api.completeMultipartUploadHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The real multipart ETag is MD5(raw MD5 bytes of part 1 || raw MD5
+	// bytes of part 2), hex-encoded, suffixed with the part count - not
+	// a plain content MD5 of the assembled bytes.
+	part1Raw, _ := hex.DecodeString(part1ETag)
+	part2Raw, _ := hex.DecodeString(part2ETag)
+	sum := md5.Sum(append(append([]byte{}, part1Raw...), part2Raw...))
+	wantETag := hex.EncodeToString(sum[:]) + "-2"
+	if !strings.Contains(w.Body.String(), wantETag) {
+		t.Errorf("response %s does not contain expected ETag %s", w.Body.String(), wantETag)
+	}
+}
+
+func TestCompleteMultipartUploadHandlerNoSuchUpload(t *testing.T) {
+	storage := newFakeStorage()
+	api := minioApi{storage: storage}
+
+	body := `<CompleteMultipartUpload></CompleteMultipartUpload>`
+	req := newMultipartRequest(t, "POST", "uploadId=missing", body,
+		map[string]string{"bucket": "bucket", "object": "object", "uploadId": "missing"})
+	w := httptest.NewRecorder()
+
+	api.completeMultipartUploadHandler(w, req)
+
+	if w.Code != errorCodeResponses[noSuchUpload].HTTPStatusCode {
+		t.Errorf("expected %d, got %d", errorCodeResponses[noSuchUpload].HTTPStatusCode, w.Code)
+	}
+}
+
+func TestAbortMultipartUploadHandler(t *testing.T) {
+	storage := newFakeStorage()
+	storage.nextUploadID = "upload-1"
+	if _, err := storage.NewMultipartUpload("bucket", "object", "text/plain"); err != nil {
+		t.Fatalf("unexpected error priming upload: %s", err)
+	}
+	api := minioApi{storage: storage}
+
+	req := newMultipartRequest(t, "DELETE", "uploadId=upload-1", "",
+		map[string]string{"bucket": "bucket", "object": "object", "uploadId": "upload-1"})
+	w := httptest.NewRecorder()
+
+	api.abortMultipartUploadHandler(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if _, ok := storage.uploads["upload-1"]; ok {
+		t.Errorf("expected upload-1 to be removed after abort")
+	}
+
+	// Aborting an already-gone upload is NoSuchUpload, not success.
+	w = httptest.NewRecorder()
+	api.abortMultipartUploadHandler(w, req)
+	if w.Code != errorCodeResponses[noSuchUpload].HTTPStatusCode {
+		t.Errorf("expected %d for repeat abort, got %d", errorCodeResponses[noSuchUpload].HTTPStatusCode, w.Code)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	mstorage "github.com/minio-io/minio/pkg/storage"
+)
+
+// getBucketPolicyHandler - GET /{bucket}?policy
+// Returns the bucket's stored policy as a plain text body, one of
+// "private", "public-read" or "public-read-write".
+func (api minioApi) getBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	policy, err := api.storage.GetBucketPolicy(bucket)
+	switch err.(type) {
+	case nil:
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(policy))
+	case mstorage.ObjectNotFound:
+		writeErrorResponse(w, req, noSuchBucket, bucket, "")
+	default:
+		writeErrorResponse(w, req, internalError, bucket, "")
+	}
+}
+
+// putBucketPolicyHandler - PUT /{bucket}?policy
+// Stores a bucket policy, one of "private", "public-read" or
+// "public-read-write", read from the request body with surrounding
+// whitespace trimmed.
+func (api minioApi) putBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		writeErrorResponse(w, req, internalError, bucket, "")
+		return
+	}
+
+	policy := mstorage.BucketPolicy(bytes.TrimSpace(body))
+	switch policy {
+	case mstorage.BucketPrivate, mstorage.BucketPublicRead, mstorage.BucketPublicReadWrite:
+	default:
+		writeErrorResponse(w, req, malformedXML, bucket, "")
+		return
+	}
+
+	switch err := api.storage.StoreBucketPolicy(bucket, policy); err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusOK)
+	case mstorage.ObjectNotFound:
+		writeErrorResponse(w, req, noSuchBucket, bucket, "")
+	default:
+		writeErrorResponse(w, req, internalError, bucket, "")
+	}
+}
+
+// deleteBucketPolicyHandler - DELETE /{bucket}?policy
+// Removes a bucket's stored policy, reverting it to private.
+func (api minioApi) deleteBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	switch err := api.storage.DeleteBucketPolicy(bucket); err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case mstorage.ObjectNotFound:
+		writeErrorResponse(w, req, noSuchBucket, bucket, "")
+	default:
+		writeErrorResponse(w, req, internalError, bucket, "")
+	}
+}
@@ -49,11 +49,30 @@ func HttpHandler(storage mstorage.Storage) http.Handler {
 	}
 
 	mux.HandleFunc("/", api.listBucketsHandler).Methods("GET")
+	mux.HandleFunc("/{bucket}", api.listMultipartUploadsHandler).Methods("GET").Queries("uploads", "")
+	mux.HandleFunc("/{bucket}", api.getBucketPolicyHandler).Methods("GET").Queries("policy", "")
 	mux.HandleFunc("/{bucket}", api.listObjectsHandler).Methods("GET")
+	mux.HandleFunc("/{bucket}", api.putBucketPolicyHandler).Methods("PUT").Queries("policy", "")
 	mux.HandleFunc("/{bucket}", api.putBucketHandler).Methods("PUT")
+	mux.HandleFunc("/{bucket}", api.deleteBucketPolicyHandler).Methods("DELETE").Queries("policy", "")
+	mux.HandleFunc("/{bucket}", api.deleteMultipleObjectsHandler).Methods("POST").Queries("delete", "")
+	mux.HandleFunc("/{bucket}", api.deleteBucketHandler).Methods("DELETE")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.listObjectPartsHandler).Methods("GET").Queries("uploadId", "{uploadId:.*}")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.getObjectHandler).Methods("GET")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.headObjectHandler).Methods("HEAD")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.putObjectPartHandler).Methods("PUT").Queries("uploadId", "{uploadId:.*}")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.putObjectHandler).Methods("PUT")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.initMultipartUploadHandler).Methods("POST").Queries("uploads", "")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.completeMultipartUploadHandler).Methods("POST").Queries("uploadId", "{uploadId:.*}")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.abortMultipartUploadHandler).Methods("DELETE").Queries("uploadId", "{uploadId:.*}")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.deleteObjectHandler).Methods("DELETE")
 
-	return validateHandler(conf, ignoreUnimplementedResources(mux))
-}
\ No newline at end of file
+	// The auth chain runs outside in: signatureHandler verifies SigV4 and
+	// marks authenticated requests, bucketPolicyHandler then lets an
+	// unauthenticated request through only if the target bucket's policy
+	// permits it anonymously.
+	handler := ignoreUnimplementedResources(mux)
+	handler = bucketPolicyHandler(storage, handler)
+	handler = signatureHandler(conf, handler)
+	return handler
+}
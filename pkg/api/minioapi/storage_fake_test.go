@@ -0,0 +1,144 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+
+	mstorage "github.com/minio-io/minio/pkg/storage"
+)
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fakeStorage is a minimal in-memory mstorage.Storage used by this
+// package's tests. Only the behaviour a given test cares about is
+// wired up; everything else returns zero values and a nil error,
+// except GetObjectMetadata, which always reports mstorage.ObjectNotFound.
+type fakeStorage struct {
+	policies map[string]mstorage.BucketPolicy
+	uploads  map[string]map[int]string // uploadID -> partNumber -> data
+
+	nextUploadID string
+	completeErr  error
+	abortErr     error
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{
+		policies: map[string]mstorage.BucketPolicy{},
+		uploads:  map[string]map[int]string{},
+	}
+}
+
+func (f *fakeStorage) ListBuckets() ([]mstorage.BucketMetadata, error) { return nil, nil }
+func (f *fakeStorage) StoreBucket(bucket string) error                 { return nil }
+func (f *fakeStorage) ListObjects(bucket, prefix string, maxKeys int) ([]mstorage.ObjectMetadata, error) {
+	return nil, nil
+}
+func (f *fakeStorage) StoreObject(bucket, object, contentType string, data io.Reader) error {
+	return nil
+}
+func (f *fakeStorage) CopyObjectToWriter(w io.Writer, bucket, object string) (int64, error) {
+	return 0, nil
+}
+func (f *fakeStorage) GetObjectMetadata(bucket, object string) (mstorage.ObjectMetadata, error) {
+	return mstorage.ObjectMetadata{}, mstorage.ObjectNotFound{Bucket: bucket, Object: object}
+}
+func (f *fakeStorage) DeleteBucket(bucket string) error         { return nil }
+func (f *fakeStorage) DeleteObject(bucket, object string) error { return nil }
+
+func (f *fakeStorage) StoreBucketPolicy(bucket string, policy mstorage.BucketPolicy) error {
+	f.policies[bucket] = policy
+	return nil
+}
+
+func (f *fakeStorage) GetBucketPolicy(bucket string) (mstorage.BucketPolicy, error) {
+	policy, ok := f.policies[bucket]
+	if !ok {
+		return "", mstorage.ObjectNotFound{Bucket: bucket}
+	}
+	return policy, nil
+}
+
+func (f *fakeStorage) DeleteBucketPolicy(bucket string) error {
+	delete(f.policies, bucket)
+	return nil
+}
+
+func (f *fakeStorage) NewMultipartUpload(bucket, object, contentType string) (string, error) {
+	f.uploads[f.nextUploadID] = map[int]string{}
+	return f.nextUploadID, nil
+}
+
+func (f *fakeStorage) CreateObjectPart(bucket, object, uploadID string, partNumber int, contentType, md5sum string, data io.Reader) (string, error) {
+	parts, ok := f.uploads[uploadID]
+	if !ok {
+		return "", mstorage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	body, err := ioutil.ReadAll(data)
+	if err != nil {
+		return "", err
+	}
+	parts[partNumber] = string(body)
+	return md5Hex(body), nil
+}
+
+func (f *fakeStorage) CompleteMultipartUpload(bucket, object, uploadID string, parts []mstorage.CompletePart) (mstorage.ObjectMetadata, error) {
+	if f.completeErr != nil {
+		return mstorage.ObjectMetadata{}, f.completeErr
+	}
+	staged, ok := f.uploads[uploadID]
+	if !ok {
+		return mstorage.ObjectMetadata{}, mstorage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	var assembled bytes.Buffer
+	for _, part := range parts {
+		assembled.WriteString(staged[part.PartNumber])
+	}
+	return mstorage.ObjectMetadata{
+		Bucket: bucket,
+		Key:    object,
+		Md5:    md5Hex(assembled.Bytes()),
+		Size:   int64(assembled.Len()),
+	}, nil
+}
+
+func (f *fakeStorage) AbortMultipartUpload(bucket, object, uploadID string) error {
+	if f.abortErr != nil {
+		return f.abortErr
+	}
+	if _, ok := f.uploads[uploadID]; !ok {
+		return mstorage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	delete(f.uploads, uploadID)
+	return nil
+}
+
+func (f *fakeStorage) ListObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (mstorage.ListPartsResult, error) {
+	return mstorage.ListPartsResult{}, nil
+}
+
+func (f *fakeStorage) ListMultipartUploads(bucket string, params mstorage.UploadsListParams) (mstorage.ListMultipartUploadsResult, error) {
+	return mstorage.ListMultipartUploadsResult{}, nil
+}
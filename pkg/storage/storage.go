@@ -0,0 +1,153 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package storage defines the generic backend interface implemented by
+// every storage driver (filesystem, in-memory, erasure coded, ...). The
+// HTTP layer in pkg/api/minioapi talks exclusively through this
+// interface so that the wire protocol and the on-disk format can evolve
+// independently of each other.
+package storage
+
+import (
+	"io"
+	"time"
+)
+
+// ObjectNotFound is returned by backends when the requested bucket,
+// object or multipart upload does not exist.
+type ObjectNotFound struct {
+	Bucket string
+	Object string
+}
+
+func (e ObjectNotFound) Error() string {
+	return "Object not found: " + e.Bucket + "#" + e.Object
+}
+
+// Storage is the interface an object storage backend must satisfy to be
+// served over the minioapi HTTP handlers.
+type Storage interface {
+	ListBuckets() ([]BucketMetadata, error)
+	StoreBucket(bucket string) error
+	ListObjects(bucket, prefix string, maxKeys int) ([]ObjectMetadata, error)
+	StoreObject(bucket, object, contentType string, data io.Reader) error
+	CopyObjectToWriter(w io.Writer, bucket, object string) (int64, error)
+	GetObjectMetadata(bucket, object string) (ObjectMetadata, error)
+	DeleteBucket(bucket string) error
+	DeleteObject(bucket, object string) error
+
+	// Bucket policy support.
+	StoreBucketPolicy(bucket string, policy BucketPolicy) error
+	GetBucketPolicy(bucket string) (BucketPolicy, error)
+	DeleteBucketPolicy(bucket string) error
+
+	// Multipart upload support.
+	NewMultipartUpload(bucket, object, contentType string) (uploadID string, err error)
+	CreateObjectPart(bucket, object, uploadID string, partNumber int, contentType, md5sum string, data io.Reader) (partMD5 string, err error)
+	CompleteMultipartUpload(bucket, object, uploadID string, parts []CompletePart) (ObjectMetadata, error)
+	AbortMultipartUpload(bucket, object, uploadID string) error
+	ListObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsResult, error)
+	ListMultipartUploads(bucket string, params UploadsListParams) (ListMultipartUploadsResult, error)
+}
+
+// BucketPolicy is the access policy attached to a bucket. It governs
+// whether unsigned requests (those with no Authorization header) are
+// allowed through as anonymous reads or writes; a request whose
+// signature fails SigV4 verification is rejected regardless of policy.
+type BucketPolicy string
+
+// Supported bucket policies.
+const (
+	BucketPrivate         BucketPolicy = "private"
+	BucketPublicRead      BucketPolicy = "public-read"
+	BucketPublicReadWrite BucketPolicy = "public-read-write"
+)
+
+// BucketMetadata container for bucket metadata.
+type BucketMetadata struct {
+	Name    string
+	Created time.Time
+}
+
+// ObjectMetadata container for object metadata.
+type ObjectMetadata struct {
+	Bucket      string
+	Key         string
+	ContentType string
+	Created     time.Time
+	Md5         string
+	Size        int64
+}
+
+// CompletePart is a single entry of the CompleteMultipartUpload request
+// body, identifying a previously uploaded part by number and the ETag
+// returned when it was staged.
+type CompletePart struct {
+	PartNumber int
+	ETag       string
+}
+
+// ObjectPartMetadata describes a single part staged against an
+// in-progress multipart upload.
+type ObjectPartMetadata struct {
+	PartNumber   int
+	ETag         string
+	LastModified time.Time
+	Size         int64
+}
+
+// ListPartsResult is returned by ListObjectParts.
+type ListPartsResult struct {
+	Bucket               string
+	Key                  string
+	UploadID             string
+	PartNumberMarker     int
+	NextPartNumberMarker int
+	MaxParts             int
+	IsTruncated          bool
+	Parts                []ObjectPartMetadata
+}
+
+// UploadMetadata describes a single in-progress multipart upload.
+type UploadMetadata struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+}
+
+// UploadsListParams holds the query parameters accepted by the "list
+// multipart uploads" operation.
+type UploadsListParams struct {
+	Delimiter      string
+	KeyMarker      string
+	MaxUploads     int
+	Prefix         string
+	UploadIDMarker string
+}
+
+// ListMultipartUploadsResult is returned by ListMultipartUploads.
+type ListMultipartUploadsResult struct {
+	Bucket             string
+	KeyMarker          string
+	UploadIDMarker     string
+	NextKeyMarker      string
+	NextUploadIDMarker string
+	Delimiter          string
+	Prefix             string
+	MaxUploads         int
+	IsTruncated        bool
+	Uploads            []UploadMetadata
+}